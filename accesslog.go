@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// accessLogBaggageKeys is the allowlist of baggage keys included in access
+// log entries, configured via ACCESS_LOG_BAGGAGE_KEYS (comma-separated).
+// Logging baggage unconditionally would let any caller inject arbitrary
+// keys into our logs, so nothing is logged unless explicitly allowed.
+func accessLogBaggageKeys() []string {
+	v := os.Getenv("ACCESS_LOG_BAGGAGE_KEYS")
+	if v == "" {
+		return nil
+	}
+	keys := strings.Split(v, ",")
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+	return keys
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code
+// written by the handler, for access logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// withAccessLog wraps h to emit a structured JSON access log line to
+// stdout once the request completes, containing the trace/span ids of
+// whichever tracing backend is active, request/response metadata, and any
+// allowlisted baggage keys. It must run "inside" tracer.WrapHandler so
+// req.Context() already carries the active span by the time h returns.
+func withAccessLog(route string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		h(rec, req)
+
+		logAccess(req, route, rec.status, time.Since(start))
+	}
+}
+
+func logAccess(req *http.Request, route string, status int, duration time.Duration) {
+	entry := map[string]interface{}{
+		"http.method":      req.Method,
+		"http.route":       route,
+		"http.status_code": status,
+		"duration_ms":      duration.Milliseconds(),
+	}
+
+	if traceID, spanID, ok := tracer.TraceContext(req.Context()); ok {
+		entry["trace_id"] = traceID
+		entry["span_id"] = spanID
+	}
+
+	bag := baggage.FromContext(req.Context())
+	for _, key := range accessLogBaggageKeys() {
+		if m := bag.Member(key); m.Key() != "" {
+			entry["baggage."+key] = m.Value()
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Println("access log: failed to marshal entry:", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// applyIncomingBaggage parses the W3C Baggage header of req, if present,
+// and attaches it to ctx using the OpenTelemetry baggage API so
+// user-defined key/value pairs (e.g. end-user, session.id) flow through to
+// the external Google Books call and into the access log, regardless of
+// which tracing backend is active.
+func applyIncomingBaggage(ctx context.Context, req *http.Request) context.Context {
+	raw := req.Header.Get("baggage")
+	if raw == "" {
+		return ctx
+	}
+	bag, err := baggage.Parse(raw)
+	if err != nil {
+		fmt.Println("baggage: ignoring malformed incoming header:", err)
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// addOutgoingBaggage sets the baggage header on an outbound request from
+// whatever baggage is carried on ctx.
+func addOutgoingBaggage(ctx context.Context, header http.Header) {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return
+	}
+	header.Set("baggage", bag.String())
+}
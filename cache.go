@@ -0,0 +1,106 @@
+package main
+
+import (
+	"container/list"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheSize = 256
+	defaultCacheTTL  = 5 * time.Minute
+)
+
+// detailsCache is a bounded, TTL-expiring, least-recently-used cache of
+// Details keyed by ISBN. It exists to keep a trace-heavy workload of
+// repeated /details/lookup calls from hammering www.googleapis.com.
+type detailsCache struct {
+	mu       sync.Mutex
+	size     int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	eviction *list.List
+}
+
+type cacheEntry struct {
+	isbn      string
+	details   *Details
+	expiresAt time.Time
+}
+
+// newDetailsCache builds a cache sized and timed from DETAILS_CACHE_SIZE
+// (entries, default 256) and DETAILS_CACHE_TTL (a time.ParseDuration
+// string, default "5m").
+func newDetailsCache() *detailsCache {
+	size := defaultCacheSize
+	if v := os.Getenv("DETAILS_CACHE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			size = n
+		}
+	}
+	ttl := defaultCacheTTL
+	if v := os.Getenv("DETAILS_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			ttl = d
+		}
+	}
+	return &detailsCache{
+		size:     size,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		eviction: list.New(),
+	}
+}
+
+// bookCache is the process-wide cache backing the ISBN lookup endpoints.
+var bookCache = newDetailsCache()
+
+// get returns the cached Details for isbn, if present and not expired.
+func (c *detailsCache) get(isbn string) (*Details, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[isbn]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.eviction.MoveToFront(el)
+	return entry.details, true
+}
+
+// put stores details for isbn, evicting the least-recently-used entry if
+// the cache is at capacity.
+func (c *detailsCache) put(isbn string, details *Details) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[isbn]; ok {
+		el.Value.(*cacheEntry).details = details
+		el.Value.(*cacheEntry).expiresAt = time.Now().Add(c.ttl)
+		c.eviction.MoveToFront(el)
+		return
+	}
+
+	el := c.eviction.PushFront(&cacheEntry{
+		isbn:      isbn,
+		details:   details,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+	c.entries[isbn] = el
+
+	if c.eviction.Len() > c.size {
+		c.removeElement(c.eviction.Back())
+	}
+}
+
+func (c *detailsCache) removeElement(el *list.Element) {
+	c.eviction.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).isbn)
+}
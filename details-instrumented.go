@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,12 +13,11 @@ import (
 	"strings"
 	"syscall"
 	"time"
-
-	instana "github.com/instana/go-sensor"
 )
 
-// Create global Instana sensor instance
-var sensor = instana.NewSensor("details")
+// errBookNotFound is returned by fetchDetailsFromExternalService when
+// Google Books has no results for the requested ISBN.
+var errBookNotFound = errors.New("books: no results for isbn")
 
 var incomingHeaders = []string{
 	// All applications should propagate x-request-id. This header is
@@ -39,10 +39,11 @@ var incomingHeaders = []string{
 	"x-datadog-parent-id",
 	"x-datadog-sampling-priority",
 
-	// W3C Trace Context. Compatible with OpenCensusAgent and Stackdriver Istio
-	// configurations.
+	// W3C Trace Context and Baggage. Compatible with OpenCensusAgent and
+	// Stackdriver Istio configurations.
 	"traceparent",
 	"tracestate",
+	"baggage",
 
 	// Cloud trace context. Compatible with OpenCensusAgent and Stackdriver Istio
 	// configurations.
@@ -60,6 +61,10 @@ var incomingHeaders = []string{
 	"x-b3-sampled",
 	"x-b3-flags",
 
+	// Apache SkyWalking trace context and correlation headers.
+	"sw8",
+	"sw8-correlation",
+
 	// Application-specific headers to forward.
 	"end-user",
 	"user-agent",
@@ -90,6 +95,9 @@ func details(w http.ResponseWriter, req *http.Request) {
 	pathParts := strings.Split(req.URL.Path, "/")
 	id, err := strconv.Atoi(pathParts[len(pathParts)-1])
 	headers := getForwardHeaders(req)
+	ctx, finishSW8 := applyIncomingSW8(req.Context(), req)
+	defer finishSW8()
+	ctx = applyIncomingBaggage(ctx, req)
 	w.Header().Add("Content-Type", "application/json")
 	var data []byte
 	if err != nil {
@@ -98,7 +106,7 @@ func details(w http.ResponseWriter, req *http.Request) {
 		}{"please provide numeric product id"})
 		w.WriteHeader(400)
 	} else {
-		data, _ = json.Marshal(getBookDetails(id, headers, req.Context()))
+		data, _ = json.Marshal(getBookDetails(id, headers, ctx))
 	}
 	fmt.Fprint(w, string(data))
 }
@@ -106,7 +114,12 @@ func details(w http.ResponseWriter, req *http.Request) {
 func getBookDetails(id int, headers http.Header, ctx context.Context) *Details {
 	if os.Getenv("ENABLE_EXTERNAL_BOOK_SERVICE") == "true" {
 		isbn := "0486424618"
-		return fetchDetailsFromExternalService(isbn, id, headers, ctx)
+		details, err := fetchDetailsFromExternalService(isbn, id, headers, ctx)
+		if err != nil {
+			fmt.Println("books: fetch failed:", err)
+			return &Details{Id: id}
+		}
+		return details
 	}
 	return &Details{
 		Id:        id,
@@ -121,7 +134,7 @@ func getBookDetails(id int, headers http.Header, ctx context.Context) *Details {
 	}
 }
 
-func fetchDetailsFromExternalService(isbn string, id int, headers http.Header, ctx context.Context) *Details {
+func fetchDetailsFromExternalService(isbn string, id int, headers http.Header, ctx context.Context) (*Details, error) {
 	proto := "https"
 	if os.Getenv("DO_NOT_ENCRYPT") == "true" {
 		proto = "http"
@@ -131,19 +144,20 @@ func fetchDetailsFromExternalService(isbn string, id int, headers http.Header, c
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
 	req, err := http.NewRequest("GET", uri, nil)
 	if err != nil {
-		return &Details{}
+		return nil, err
 	}
-	client := &http.Client{Transport: instana.RoundTripper(sensor, tr), Timeout: 5 * time.Second}
+	parentSW8, _ := sw8FromContext(ctx)
+	req.Header.Set("sw8", outgoingSW8(parentSW8, "www.googleapis.com", "/books/v1/volumes"))
+	addOutgoingBaggage(ctx, req.Header)
+	client := &http.Client{Transport: tracer.WrapTransport(tr), Timeout: 5 * time.Second}
 	res, err := client.Do(req.WithContext(ctx))
 	if err != nil {
-		fmt.Println(err)
-		return &Details{}
+		return nil, err
 	}
+	defer res.Body.Close()
 	if res.StatusCode != 200 {
-		fmt.Println(res.Status)
-		return &Details{}
+		return nil, fmt.Errorf("books: unexpected status %s", res.Status)
 	}
-	defer res.Body.Close()
 
 	rec := &struct {
 		Items []struct {
@@ -162,9 +176,19 @@ func fetchDetailsFromExternalService(isbn string, id int, headers http.Header, c
 		} `json:"items"`
 	}{}
 
-	json.NewDecoder(res.Body).Decode(rec)
+	if err := json.NewDecoder(res.Body).Decode(rec); err != nil {
+		return nil, err
+	}
+	if len(rec.Items) == 0 {
+		return nil, errBookNotFound
+	}
 	book := rec.Items[0].VolumeInfo
 
+	author := ""
+	if len(book.Authors) > 0 {
+		author = book.Authors[0]
+	}
+
 	language, printType := "unknown", "unknown"
 	if book.PrintType == "BOOK" {
 		printType = "paperback"
@@ -180,7 +204,7 @@ func fetchDetailsFromExternalService(isbn string, id int, headers http.Header, c
 
 	return &Details{
 		Id:        id,
-		Author:    book.Authors[0],
+		Author:    author,
 		Year:      year,
 		Type:      printType,
 		Pages:     book.PageCount,
@@ -188,7 +212,7 @@ func fetchDetailsFromExternalService(isbn string, id int, headers http.Header, c
 		Language:  language,
 		ISBN_10:   isbnIdentifier["ISBN_10"],
 		ISBN_13:   isbnIdentifier["ISBN_13"],
-	}
+	}, nil
 }
 
 func getForwardHeaders(req *http.Request) http.Header {
@@ -209,17 +233,24 @@ func main() {
 
 	port := os.Args[1]
 
+	grpcServer, err := startGRPCServer(":" + grpcPort())
+	if err != nil {
+		fmt.Println("grpc: failed to start server:", err)
+	}
+
 	// Catch SIGTERM
 	go func() {
 		sig := make(chan os.Signal, 1)
 		signal.Notify(sig, syscall.SIGTERM)
 		<-sig
-		os.Exit(0)
+		shutdown(grpcServer)
 	}()
 
-	http.HandleFunc("/details/", instana.TracingHandlerFunc(sensor, "/details", details))
-	http.HandleFunc("/details", instana.TracingHandlerFunc(sensor, "/details", details))
-	http.HandleFunc("/health", instana.TracingHandlerFunc(sensor, "/health", health))
+	http.HandleFunc("/details/by-isbn/", tracer.WrapHandler("/details/by-isbn", withAccessLog("/details/by-isbn", detailsByISBN)))
+	http.HandleFunc("/details/lookup", tracer.WrapHandler("/details/lookup", withAccessLog("/details/lookup", detailsLookup)))
+	http.HandleFunc("/details/", tracer.WrapHandler("/details", withAccessLog("/details", details)))
+	http.HandleFunc("/details", tracer.WrapHandler("/details", withAccessLog("/details", details)))
+	http.HandleFunc("/health", tracer.WrapHandler("/health", withAccessLog("/health", health)))
 
 	http.ListenAndServe(":"+port, nil)
 }
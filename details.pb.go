@@ -0,0 +1,43 @@
+// Hand-written to mirror proto/details.proto. There is no protoc/protoc-gen-go
+// toolchain wired into this repo, so this is not regenerated output — update
+// it and details_grpc.pb.go together if the schema changes.
+
+package main
+
+import "fmt"
+
+// GetDetailsRequest is the request message for DetailsService.GetDetails.
+type GetDetailsRequest struct {
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *GetDetailsRequest) Reset()         { *m = GetDetailsRequest{} }
+func (m *GetDetailsRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*GetDetailsRequest) ProtoMessage()    {}
+
+// LookupByISBNRequest is the request message for
+// DetailsService.LookupByISBN.
+type LookupByISBNRequest struct {
+	Isbn string `protobuf:"bytes,1,opt,name=isbn,proto3" json:"isbn,omitempty"`
+}
+
+func (m *LookupByISBNRequest) Reset()         { *m = LookupByISBNRequest{} }
+func (m *LookupByISBNRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*LookupByISBNRequest) ProtoMessage()    {}
+
+// DetailsResponse mirrors the Details struct returned by the HTTP API.
+type DetailsResponse struct {
+	Id        int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Author    string `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Year      int32  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Type      string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Pages     int32  `protobuf:"varint,5,opt,name=pages,proto3" json:"pages,omitempty"`
+	Publisher string `protobuf:"bytes,6,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Language  string `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
+	Isbn_10   string `protobuf:"bytes,8,opt,name=isbn_10,json=isbn10,proto3" json:"isbn_10,omitempty"`
+	Isbn_13   string `protobuf:"bytes,9,opt,name=isbn_13,json=isbn13,proto3" json:"isbn_13,omitempty"`
+}
+
+func (m *DetailsResponse) Reset()         { *m = DetailsResponse{} }
+func (m *DetailsResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*DetailsResponse) ProtoMessage()    {}
@@ -0,0 +1,119 @@
+// Hand-written to mirror proto/details.proto. There is no protoc/protoc-gen-go-grpc
+// toolchain wired into this repo, so this is not regenerated output — update
+// it and details.pb.go together if the schema changes.
+
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DetailsServiceClient is the client API for DetailsService.
+type DetailsServiceClient interface {
+	GetDetails(ctx context.Context, in *GetDetailsRequest, opts ...grpc.CallOption) (*DetailsResponse, error)
+	LookupByISBN(ctx context.Context, in *LookupByISBNRequest, opts ...grpc.CallOption) (*DetailsResponse, error)
+}
+
+type detailsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDetailsServiceClient(cc grpc.ClientConnInterface) DetailsServiceClient {
+	return &detailsServiceClient{cc}
+}
+
+func (c *detailsServiceClient) GetDetails(ctx context.Context, in *GetDetailsRequest, opts ...grpc.CallOption) (*DetailsResponse, error) {
+	out := new(DetailsResponse)
+	err := c.cc.Invoke(ctx, "/details.DetailsService/GetDetails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detailsServiceClient) LookupByISBN(ctx context.Context, in *LookupByISBNRequest, opts ...grpc.CallOption) (*DetailsResponse, error) {
+	out := new(DetailsResponse)
+	err := c.cc.Invoke(ctx, "/details.DetailsService/LookupByISBN", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DetailsServiceServer is the server API for DetailsService.
+type DetailsServiceServer interface {
+	GetDetails(context.Context, *GetDetailsRequest) (*DetailsResponse, error)
+	LookupByISBN(context.Context, *LookupByISBNRequest) (*DetailsResponse, error)
+}
+
+// UnimplementedDetailsServiceServer can be embedded to have forward
+// compatible implementations.
+type UnimplementedDetailsServiceServer struct{}
+
+func (UnimplementedDetailsServiceServer) GetDetails(context.Context, *GetDetailsRequest) (*DetailsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDetails not implemented")
+}
+func (UnimplementedDetailsServiceServer) LookupByISBN(context.Context, *LookupByISBNRequest) (*DetailsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LookupByISBN not implemented")
+}
+
+func RegisterDetailsServiceServer(s grpc.ServiceRegistrar, srv DetailsServiceServer) {
+	s.RegisterService(&_DetailsService_serviceDesc, srv)
+}
+
+func _DetailsService_GetDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetailsServiceServer).GetDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/details.DetailsService/GetDetails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetailsServiceServer).GetDetails(ctx, req.(*GetDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetailsService_LookupByISBN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupByISBNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetailsServiceServer).LookupByISBN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/details.DetailsService/LookupByISBN",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetailsServiceServer).LookupByISBN(ctx, req.(*LookupByISBNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DetailsService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "details.DetailsService",
+	HandlerType: (*DetailsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDetails",
+			Handler:    _DetailsService_GetDetails_Handler,
+		},
+		{
+			MethodName: "LookupByISBN",
+			Handler:    _DetailsService_LookupByISBN_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/details.proto",
+}
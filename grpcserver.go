@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	octrace "go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcDetailsServer implements DetailsServiceServer on top of the same
+// getBookDetails/getBookDetailsByISBN core the HTTP handlers use.
+type grpcDetailsServer struct {
+	UnimplementedDetailsServiceServer
+}
+
+func (s *grpcDetailsServer) GetDetails(ctx context.Context, req *GetDetailsRequest) (*DetailsResponse, error) {
+	return detailsToResponse(getBookDetails(int(req.Id), http.Header{}, ctx)), nil
+}
+
+func (s *grpcDetailsServer) LookupByISBN(ctx context.Context, req *LookupByISBNRequest) (*DetailsResponse, error) {
+	if !validateISBN(req.Isbn) {
+		return nil, status.Error(codes.InvalidArgument, "please provide a valid ISBN-10 or ISBN-13")
+	}
+	details, err := getBookDetailsByISBN(normalizeISBN(req.Isbn), http.Header{}, ctx)
+	if errors.Is(err, errBookNotFound) {
+		return nil, status.Error(codes.NotFound, "no book found for isbn")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "failed to fetch book details")
+	}
+	return detailsToResponse(details), nil
+}
+
+func detailsToResponse(d *Details) *DetailsResponse {
+	return &DetailsResponse{
+		Id:        int32(d.Id),
+		Author:    d.Author,
+		Year:      int32(d.Year),
+		Type:      d.Type,
+		Pages:     int32(d.Pages),
+		Publisher: d.Publisher,
+		Language:  d.Language,
+		Isbn_10:   d.ISBN_10,
+		Isbn_13:   d.ISBN_13,
+	}
+}
+
+// grpcPort returns the address the gRPC server listens on, configured via
+// the GRPC_PORT environment variable.
+func grpcPort() string {
+	if p := os.Getenv("GRPC_PORT"); p != "" {
+		return p
+	}
+	return "9090"
+}
+
+// startGRPCServer starts the gRPC server in the background and returns it
+// so the caller can drive a graceful shutdown.
+func startGRPCServer(addr string) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := grpc.NewServer(grpc.UnaryInterceptor(grpcTraceBinServerInterceptor))
+	RegisterDetailsServiceServer(srv, &grpcDetailsServer{})
+
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			fmt.Println("grpc: server stopped:", err)
+		}
+	}()
+
+	return srv, nil
+}
+
+// shutdownGracePeriod bounds how long shutdown waits for in-flight gRPC
+// calls to finish before forcing them closed, so a stuck client can't keep
+// the process alive past its termination grace period.
+const shutdownGracePeriod = 5 * time.Second
+
+// shutdown is the common shutdown path for both the HTTP and gRPC servers:
+// it stops accepting new gRPC work and flushes the active tracing backend
+// before exiting, so in-flight spans aren't dropped the way a bare
+// os.Exit(0) would drop them.
+func shutdown(grpcServer *grpc.Server) {
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownGracePeriod):
+			grpcServer.Stop()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		fmt.Println("tracer: shutdown failed:", err)
+	}
+
+	os.Exit(0)
+}
+
+// grpcTraceBinServerInterceptor starts a span for every unary RPC, mirroring
+// how tracer.WrapHandler instruments every HTTP route. When the call carries
+// grpc-trace-bin (the OpenCensus/Stackdriver binary trace context format,
+// already forwarded by incomingHeaders on the HTTP side), the span is made a
+// genuine child of it via Tracer.ContinueRemoteTrace, so a trace begun by a
+// mesh sidecar over gRPC continues in the active tracing backend rather than
+// starting over with an unrelated trace id. If the backend can't represent
+// the incoming ids (e.g. TRACING_BACKEND=none), this falls back to a fresh
+// span with the ids attached as tags, so they're still visible in the trace.
+func grpcTraceBinServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var sc octrace.SpanContext
+	var haveSC bool
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("grpc-trace-bin"); len(vals) > 0 {
+			sc, haveSC = propagation.FromBinary([]byte(vals[0]))
+		}
+	}
+
+	var span Span
+	var spanCtx context.Context
+	if haveSC {
+		var ok bool
+		span, spanCtx, ok = tracer.ContinueRemoteTrace(ctx, info.FullMethod, sc.TraceID.String(), sc.SpanID.String())
+		if !ok {
+			haveSC = false
+		}
+	}
+	if !haveSC {
+		span, spanCtx = tracer.StartSpan(ctx, info.FullMethod)
+	}
+	defer span.Finish()
+
+	if haveSC {
+		span.SetTag("grpc_trace_bin.trace_id", sc.TraceID.String())
+		span.SetTag("grpc_trace_bin.span_id", sc.SpanID.String())
+	}
+
+	return handler(spanCtx, req)
+}
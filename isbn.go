@@ -0,0 +1,71 @@
+package main
+
+import "strconv"
+
+// normalizeISBN strips the hyphens/spaces that ISBNs are commonly typed
+// with, so "978-0-486-42461-8" and "9780486424618" validate the same way.
+func normalizeISBN(isbn string) string {
+	out := make([]byte, 0, len(isbn))
+	for i := 0; i < len(isbn); i++ {
+		switch c := isbn[i]; c {
+		case '-', ' ':
+			continue
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(out)
+}
+
+// validateISBN reports whether isbn is a well-formed ISBN-10 or ISBN-13,
+// including its checksum digit.
+func validateISBN(isbn string) bool {
+	isbn = normalizeISBN(isbn)
+	switch len(isbn) {
+	case 10:
+		return validateISBN10(isbn)
+	case 13:
+		return validateISBN13(isbn)
+	default:
+		return false
+	}
+}
+
+// validateISBN10 checks the ISBN-10 checksum: the weighted sum of its 10
+// digits (weights 10 down to 1) must be divisible by 11. The final digit
+// may be "X", representing 10.
+func validateISBN10(isbn string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var digit int
+		if i == 9 && (isbn[i] == 'X' || isbn[i] == 'x') {
+			digit = 10
+		} else {
+			d, err := strconv.Atoi(string(isbn[i]))
+			if err != nil {
+				return false
+			}
+			digit = d
+		}
+		sum += (10 - i) * digit
+	}
+	return sum%11 == 0
+}
+
+// validateISBN13 checks the ISBN-13/EAN-13 checksum: digits alternate
+// weights of 1 and 3, and the weighted sum must be divisible by 10.
+func validateISBN13(isbn string) bool {
+	sum := 0
+	for i := 0; i < 13; i++ {
+		digit, err := strconv.Atoi(string(isbn[i]))
+		if err != nil {
+			return false
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * digit
+	}
+	return sum%10 == 0
+}
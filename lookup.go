@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// detailsLookup handles POST /details/lookup, accepting {"isbn": "..."}
+// and returning the same Details shape as the numeric-id /details/{id}
+// endpoint, looked up by ISBN instead.
+func detailsLookup(w http.ResponseWriter, req *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		fmt.Fprint(w, `{"error":"POST required"}`)
+		return
+	}
+
+	var body struct {
+		ISBN string `json:"isbn"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeISBNError(w)
+		return
+	}
+
+	respondWithISBN(w, req, body.ISBN)
+}
+
+// detailsByISBN handles GET /details/by-isbn/{isbn}.
+func detailsByISBN(w http.ResponseWriter, req *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	pathParts := strings.Split(req.URL.Path, "/")
+	isbn := pathParts[len(pathParts)-1]
+	respondWithISBN(w, req, isbn)
+}
+
+func respondWithISBN(w http.ResponseWriter, req *http.Request, isbn string) {
+	if !validateISBN(isbn) {
+		writeISBNError(w)
+		return
+	}
+
+	headers := getForwardHeaders(req)
+	ctx, finishSW8 := applyIncomingSW8(req.Context(), req)
+	defer finishSW8()
+	ctx = applyIncomingBaggage(ctx, req)
+
+	details, err := getBookDetailsByISBN(normalizeISBN(isbn), headers, ctx)
+	if errors.Is(err, errBookNotFound) {
+		writeISBNNotFound(w)
+		return
+	}
+	if err != nil {
+		fmt.Println("books: fetch failed:", err)
+		writeISBNUpstreamError(w)
+		return
+	}
+
+	data, _ := json.Marshal(details)
+	fmt.Fprint(w, string(data))
+}
+
+func writeISBNError(w http.ResponseWriter) {
+	data, _ := json.Marshal(&struct {
+		Error string `json:"error"`
+	}{"please provide a valid ISBN-10 or ISBN-13"})
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprint(w, string(data))
+}
+
+func writeISBNNotFound(w http.ResponseWriter) {
+	data, _ := json.Marshal(&struct {
+		Error string `json:"error"`
+	}{"no book found for isbn"})
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprint(w, string(data))
+}
+
+func writeISBNUpstreamError(w http.ResponseWriter) {
+	data, _ := json.Marshal(&struct {
+		Error string `json:"error"`
+	}{"failed to fetch book details"})
+	w.WriteHeader(http.StatusBadGateway)
+	fmt.Fprint(w, string(data))
+}
+
+// getBookDetailsByISBN looks up Details for isbn, serving from bookCache
+// when possible and otherwise falling through to
+// fetchDetailsFromExternalService, populating the cache only on a
+// successful fetch so a transient upstream failure can't poison the cache
+// with empty Details for the full TTL. A child span is created either way
+// so cache hits remain visible in traces.
+func getBookDetailsByISBN(isbn string, headers http.Header, ctx context.Context) (*Details, error) {
+	span, ctx := tracer.StartSpan(ctx, "cache.lookup")
+	defer span.Finish()
+
+	if cached, ok := bookCache.get(isbn); ok {
+		span.SetTag("cache.hit", true)
+		return cached, nil
+	}
+	span.SetTag("cache.hit", false)
+
+	if os.Getenv("ENABLE_EXTERNAL_BOOK_SERVICE") != "true" {
+		return &Details{
+			Id:        0,
+			Author:    "William Shakespeare",
+			Year:      1595,
+			Type:      "paperback",
+			Pages:     200,
+			Publisher: "PublisherA",
+			Language:  "English",
+			ISBN_10:   "1234567890",
+			ISBN_13:   "123-1234567890",
+		}, nil
+	}
+
+	details, err := fetchDetailsFromExternalService(isbn, 0, headers, ctx)
+	if err != nil {
+		return nil, err
+	}
+	bookCache.put(isbn, details)
+	return details, nil
+}
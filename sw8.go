@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// sw8ServiceName and sw8ServiceInstance identify this service when it
+// appears as the parent of a SkyWalking span. There's no real instance
+// registry here, so the instance is just a static label.
+const (
+	sw8ServiceName     = "details"
+	sw8ServiceInstance = "details-1"
+)
+
+// SW8Context holds the fields carried by Apache SkyWalking's sw8 trace
+// context header:
+// https://skywalking.apache.org/docs/main/latest/en/api/x-process-propagation-headers-v3/
+type SW8Context struct {
+	Sample         bool
+	TraceID        string
+	SegmentID      string
+	SpanID         string
+	ParentService  string
+	ParentInstance string
+	ParentEndpoint string
+	Peer           string
+}
+
+type sw8ContextKey struct{}
+
+// withSW8Context attaches an SW8Context parsed from an incoming request to
+// ctx so downstream calls (e.g. fetchDetailsFromExternalService) can
+// continue the same SkyWalking trace.
+func withSW8Context(ctx context.Context, sw *SW8Context) context.Context {
+	return context.WithValue(ctx, sw8ContextKey{}, sw)
+}
+
+func sw8FromContext(ctx context.Context) (*SW8Context, bool) {
+	sw, ok := ctx.Value(sw8ContextKey{}).(*SW8Context)
+	return sw, ok
+}
+
+// parseSW8 decodes the value of an incoming sw8 header. The header has 8
+// hyphen-separated fields; per the SkyWalking spec only trace id, segment
+// id, parent service, parent instance and parent endpoint are base64
+// encoded, while sample, span id and peer are plain values.
+func parseSW8(value string) (*SW8Context, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 8 {
+		return nil, fmt.Errorf("sw8: expected 8 fields, got %d", len(parts))
+	}
+
+	traceID, err := decodeSW8Field(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("sw8: trace id: %w", err)
+	}
+	segmentID, err := decodeSW8Field(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("sw8: segment id: %w", err)
+	}
+	parentService, err := decodeSW8Field(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("sw8: parent service: %w", err)
+	}
+	parentInstance, err := decodeSW8Field(parts[5])
+	if err != nil {
+		return nil, fmt.Errorf("sw8: parent instance: %w", err)
+	}
+	parentEndpoint, err := decodeSW8Field(parts[6])
+	if err != nil {
+		return nil, fmt.Errorf("sw8: parent endpoint: %w", err)
+	}
+
+	return &SW8Context{
+		Sample:         parts[0] == "1",
+		TraceID:        traceID,
+		SegmentID:      segmentID,
+		SpanID:         parts[3],
+		ParentService:  parentService,
+		ParentInstance: parentInstance,
+		ParentEndpoint: parentEndpoint,
+		Peer:           parts[7],
+	}, nil
+}
+
+func decodeSW8Field(field string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(field)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// encode renders the context as an outgoing sw8 header value.
+func (sw *SW8Context) encode() string {
+	sample := "0"
+	if sw.Sample {
+		sample = "1"
+	}
+	return strings.Join([]string{
+		sample,
+		encodeSW8Field(sw.TraceID),
+		encodeSW8Field(sw.SegmentID),
+		sw.SpanID,
+		encodeSW8Field(sw.ParentService),
+		encodeSW8Field(sw.ParentInstance),
+		encodeSW8Field(sw.ParentEndpoint),
+		sw.Peer,
+	}, "-")
+}
+
+func encodeSW8Field(field string) string {
+	return base64.StdEncoding.EncodeToString([]byte(field))
+}
+
+// newSegmentID generates a new random SkyWalking segment id for the span
+// this service creates when it forwards a request.
+func newSegmentID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "0"
+	}
+	return hex.EncodeToString(b)
+}
+
+// outgoingSW8 builds the sw8 header this service should send when calling
+// the external Google Books service, continuing the trace carried by
+// parent if one was extracted from the incoming request, or starting a new
+// one otherwise.
+func outgoingSW8(parent *SW8Context, peer, endpoint string) string {
+	sw := &SW8Context{
+		Sample:         true,
+		SegmentID:      newSegmentID(),
+		SpanID:         "0",
+		ParentService:  sw8ServiceName,
+		ParentInstance: sw8ServiceInstance,
+		ParentEndpoint: endpoint,
+		Peer:           peer,
+	}
+	if parent != nil {
+		sw.TraceID = parent.TraceID
+		sw.Sample = parent.Sample
+	} else {
+		sw.TraceID = newSegmentID()
+	}
+	return sw.encode()
+}
+
+// applyIncomingSW8 parses the sw8 header of req, if present, and stitches it
+// into the active tracing backend: it starts a child span tagged with the
+// SkyWalking trace/segment id so the resulting trace shows up as a
+// continuation of the incoming SkyWalking trace rather than two disconnected
+// traces that merely share a forwarded header. It returns a context carrying
+// both the span and the parsed SW8Context (the latter for building the
+// outgoing header to Google Books), and a finish func the caller must defer.
+// Malformed headers are logged and ignored rather than failing the request.
+func applyIncomingSW8(ctx context.Context, req *http.Request) (context.Context, func()) {
+	raw := req.Header.Get("sw8")
+	if raw == "" {
+		return ctx, func() {}
+	}
+	sw, err := parseSW8(raw)
+	if err != nil {
+		fmt.Println("sw8: ignoring malformed incoming header:", err)
+		return ctx, func() {}
+	}
+
+	ctx = withSW8Context(ctx, sw)
+	span, ctx := tracer.StartSpan(ctx, "sw8.continuation")
+	span.SetTag("sw8.trace_id", sw.TraceID)
+	span.SetTag("sw8.segment_id", sw.SegmentID)
+	span.SetTag("sw8.span_id", sw.SpanID)
+	span.SetTag("sw8.sample", sw.Sample)
+	return ctx, span.Finish
+}
@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+func TestParseSW8Malformed(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+	}{
+		{"too few fields", "1-dGVzdA==-dGVzdA=="},
+		{"too many fields", "1-dGVzdA==-dGVzdA==-0-dGVzdA==-dGVzdA==-dGVzdA==-10.0.0.1:443-extra"},
+		{"invalid base64 trace id", "1-not-base64!-dGVzdA==-0-dGVzdA==-dGVzdA==-dGVzdA==-10.0.0.1:443"},
+		{"empty string", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := parseSW8(c.value); err == nil {
+				t.Errorf("parseSW8(%q) expected an error, got nil", c.value)
+			}
+		})
+	}
+}
+
+func TestParseSW8MissingCorrelation(t *testing.T) {
+	// sw8 and sw8-correlation are independent headers; parseSW8 only ever
+	// sees the sw8 value, so a well-formed sw8 header must parse correctly
+	// whether or not a correlation header accompanied it.
+	value := "1-" + encodeSW8Field("1234") + "-" + encodeSW8Field("5678") +
+		"-0-" + encodeSW8Field("service-a") + "-" + encodeSW8Field("instance-a") +
+		"-" + encodeSW8Field("/checkout") + "-10.0.0.1:443"
+
+	sw, err := parseSW8(value)
+	if err != nil {
+		t.Fatalf("parseSW8(%q) unexpected error: %v", value, err)
+	}
+	if sw.TraceID != "1234" || sw.SegmentID != "5678" {
+		t.Errorf("parseSW8(%q) = %+v, want TraceID=1234 SegmentID=5678", value, sw)
+	}
+}
+
+func TestSW8SamplingFlagRoundTrip(t *testing.T) {
+	for _, sample := range []bool{true, false} {
+		sw := &SW8Context{
+			Sample:         sample,
+			TraceID:        "trace-1",
+			SegmentID:      "segment-1",
+			SpanID:         "0",
+			ParentService:  sw8ServiceName,
+			ParentInstance: sw8ServiceInstance,
+			ParentEndpoint: "/details",
+			Peer:           "10.0.0.1:443",
+		}
+
+		parsed, err := parseSW8(sw.encode())
+		if err != nil {
+			t.Fatalf("parseSW8(%s.encode()) unexpected error: %v", sw.SegmentID, err)
+		}
+		if parsed.Sample != sample {
+			t.Errorf("sample flag round-trip: got %v, want %v", parsed.Sample, sample)
+		}
+	}
+}
+
+func TestSW8EncodeParseRoundTrip(t *testing.T) {
+	sw := &SW8Context{
+		Sample:         true,
+		TraceID:        "1234567890",
+		SegmentID:      "abcdef",
+		SpanID:         "3",
+		ParentService:  "service-a",
+		ParentInstance: "service-a-1",
+		ParentEndpoint: "/checkout",
+		Peer:           "10.0.0.1:443",
+	}
+
+	parsed, err := parseSW8(sw.encode())
+	if err != nil {
+		t.Fatalf("parseSW8(%s) unexpected error: %v", sw.encode(), err)
+	}
+	if *parsed != *sw {
+		t.Errorf("round trip mismatch: got %+v, want %+v", parsed, sw)
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	instana "github.com/instana/go-sensor"
+	ot "github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer abstracts the tracing backend so the service can switch between
+// Instana and OpenTelemetry (or disable tracing entirely) without any
+// changes to the HTTP handlers. Selection happens in NewTracer, driven by
+// the TRACING_BACKEND environment variable.
+type Tracer interface {
+	// WrapHandler instruments an http.HandlerFunc registered at path.
+	WrapHandler(path string, h http.HandlerFunc) http.HandlerFunc
+	// WrapTransport instruments an outbound http.RoundTripper.
+	WrapTransport(rt http.RoundTripper) http.RoundTripper
+	// Shutdown flushes any buffered spans. It must be safe to call more
+	// than once.
+	Shutdown(ctx context.Context) error
+	// StartSpan starts a child span of whatever span is active in ctx (if
+	// any) and returns it alongside a context carrying it. Callers must
+	// call Finish on the returned Span.
+	StartSpan(ctx context.Context, operationName string) (Span, context.Context)
+	// TraceContext returns the trace and span ids of whatever span is
+	// active in ctx, for access-log correlation. ok is false if no span is
+	// active.
+	TraceContext(ctx context.Context) (traceID, spanID string, ok bool)
+	// ContinueRemoteTrace starts a span that is a genuine child of a
+	// remote trace carried by another system (e.g. an OpenCensus
+	// grpc-trace-bin header), identified by hex-encoded trace/span ids in
+	// the same format TraceContext returns. ok is false if the active
+	// backend's id space can't represent the given ids, in which case the
+	// caller should fall back to StartSpan plus tagging.
+	ContinueRemoteTrace(ctx context.Context, operationName, traceIDHex, spanIDHex string) (Span, context.Context, bool)
+}
+
+// Span is the subset of a tracing span needed to annotate ad-hoc work,
+// such as tagging a cache lookup, without coupling callers to a specific
+// backend's span type.
+type Span interface {
+	SetTag(key string, value interface{})
+	Finish()
+}
+
+// tracer is the globally active tracing backend, selected at startup.
+var tracer = NewTracer()
+
+// NewTracer selects a Tracer implementation based on the TRACING_BACKEND
+// environment variable. Recognized values are "instana" (default),
+// "otel", and "none".
+func NewTracer() Tracer {
+	switch os.Getenv("TRACING_BACKEND") {
+	case "otel":
+		t, err := newOtelTracer()
+		if err != nil {
+			fmt.Println("otel: failed to initialize, falling back to no tracing:", err)
+			return noopTracer{}
+		}
+		return t
+	case "none":
+		return noopTracer{}
+	default:
+		return newInstanaTracer()
+	}
+}
+
+// instanaTracer adapts the Instana go-sensor SDK to the Tracer interface.
+type instanaTracer struct {
+	sensor instana.TracerLogger
+}
+
+func newInstanaTracer() *instanaTracer {
+	return &instanaTracer{sensor: instana.NewSensor("details")}
+}
+
+func (t *instanaTracer) WrapHandler(path string, h http.HandlerFunc) http.HandlerFunc {
+	return instana.TracingHandlerFunc(t.sensor, path, h)
+}
+
+func (t *instanaTracer) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return instana.RoundTripper(t.sensor, rt)
+}
+
+func (t *instanaTracer) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (t *instanaTracer) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	span, spanCtx := ot.StartSpanFromContextWithTracer(ctx, t.sensor.Tracer(), operationName)
+	return otSpan{span}, spanCtx
+}
+
+// otSpan adapts an OpenTracing span (as used by the Instana SDK) to Span.
+type otSpan struct {
+	span ot.Span
+}
+
+func (s otSpan) SetTag(key string, value interface{}) { s.span.SetTag(key, value) }
+func (s otSpan) Finish()                              { s.span.Finish() }
+
+func (t *instanaTracer) TraceContext(ctx context.Context) (string, string, bool) {
+	span := ot.SpanFromContext(ctx)
+	if span == nil {
+		return "", "", false
+	}
+	sc, ok := span.Context().(instana.SpanContext)
+	if !ok {
+		return "", "", false
+	}
+	return instana.FormatID(sc.TraceID), instana.FormatID(sc.SpanID), true
+}
+
+// ContinueRemoteTrace links a span to a remote trace/span id pair wider
+// than Instana's own 64-bit id space (e.g. a 128-bit OpenCensus trace id)
+// by taking the low 64 bits of traceIDHex, rather than failing outright.
+// This means the linkage degrades to "correct span ancestry, truncated
+// trace id" instead of a true 1:1 continuation.
+func (t *instanaTracer) ContinueRemoteTrace(ctx context.Context, operationName, traceIDHex, spanIDHex string) (Span, context.Context, bool) {
+	traceID, ok := parseLow64Hex(traceIDHex)
+	if !ok {
+		return nil, ctx, false
+	}
+	spanID, ok := parseLow64Hex(spanIDHex)
+	if !ok {
+		return nil, ctx, false
+	}
+
+	parent := instana.SpanContext{TraceID: traceID, SpanID: spanID}
+	span := t.sensor.Tracer().StartSpan(operationName, ot.ChildOf(parent))
+	spanCtx := ot.ContextWithSpan(ctx, span)
+	return otSpan{span}, spanCtx, true
+}
+
+// parseLow64Hex parses the low 64 bits (last 16 hex digits) of a hex string
+// into an int64, so a 128-bit id can be mapped onto Instana's 64-bit ids.
+func parseLow64Hex(h string) (int64, bool) {
+	if len(h) == 0 {
+		return 0, false
+	}
+	if len(h) > 16 {
+		h = h[len(h)-16:]
+	}
+	v, err := strconv.ParseUint(h, 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int64(v), true
+}
+
+// otelTracer adapts an OpenTelemetry SDK TracerProvider, exporting spans via
+// OTLP (HTTP or gRPC, depending on OTEL_EXPORTER_OTLP_PROTOCOL) to the
+// collector endpoint configured by OTEL_EXPORTER_OTLP_ENDPOINT.
+type otelTracer struct {
+	provider *sdktrace.TracerProvider
+}
+
+func newOtelTracer() (*otelTracer, error) {
+	ctx := context.Background()
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "details"
+	}
+
+	exporter, err := newOtlpExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return &otelTracer{provider: provider}, nil
+}
+
+// newOtlpExporter builds an OTLP span exporter. The protocol defaults to
+// "http/protobuf" and can be overridden with OTEL_EXPORTER_OTLP_PROTOCOL=grpc,
+// matching the behavior of the other OpenTelemetry language SDKs.
+func newOtlpExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL") == "grpc" {
+		return otlptracegrpc.New(ctx)
+	}
+	return otlptracehttp.New(ctx)
+}
+
+func (t *otelTracer) WrapHandler(path string, h http.HandlerFunc) http.HandlerFunc {
+	handler := otelhttp.NewHandler(h, path, otelhttp.WithTracerProvider(t.provider))
+	return func(w http.ResponseWriter, req *http.Request) {
+		handler.ServeHTTP(w, req)
+	}
+}
+
+func (t *otelTracer) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(rt, otelhttp.WithTracerProvider(t.provider))
+}
+
+func (t *otelTracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+func (t *otelTracer) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	spanCtx, span := t.provider.Tracer("details").Start(ctx, operationName)
+	return otelSpan{span}, spanCtx
+}
+
+// otelSpan adapts an OpenTelemetry SDK span to Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetTag(key string, value interface{}) {
+	s.span.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+}
+
+func (s otelSpan) Finish() { s.span.End() }
+
+func (t *otelTracer) TraceContext(ctx context.Context) (string, string, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
+
+// ContinueRemoteTrace links a span to a remote trace/span id pair by
+// marking them as a remote parent in ctx before starting the child span,
+// per the OpenTelemetry API's own mechanism for cross-process propagation
+// (see trace.ContextWithRemoteSpanContext).
+func (t *otelTracer) ContinueRemoteTrace(ctx context.Context, operationName, traceIDHex, spanIDHex string) (Span, context.Context, bool) {
+	traceID, err := trace.TraceIDFromHex(traceIDHex)
+	if err != nil {
+		return nil, ctx, false
+	}
+	spanID, err := trace.SpanIDFromHex(spanIDHex)
+	if err != nil {
+		return nil, ctx, false
+	}
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	spanCtx, span := t.provider.Tracer("details").Start(trace.ContextWithRemoteSpanContext(ctx, parent), operationName)
+	return otelSpan{span}, spanCtx, true
+}
+
+// noopTracer disables tracing entirely (TRACING_BACKEND=none).
+type noopTracer struct{}
+
+func (noopTracer) WrapHandler(path string, h http.HandlerFunc) http.HandlerFunc { return h }
+func (noopTracer) WrapTransport(rt http.RoundTripper) http.RoundTripper         { return rt }
+func (noopTracer) Shutdown(ctx context.Context) error                           { return nil }
+func (noopTracer) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	return noopSpan{}, ctx
+}
+func (noopTracer) TraceContext(ctx context.Context) (string, string, bool) { return "", "", false }
+func (noopTracer) ContinueRemoteTrace(ctx context.Context, operationName, traceIDHex, spanIDHex string) (Span, context.Context, bool) {
+	return noopSpan{}, ctx, true
+}
+
+// noopSpan is the Span used when tracing is disabled.
+type noopSpan struct{}
+
+func (noopSpan) SetTag(key string, value interface{}) {}
+func (noopSpan) Finish()                              {}